@@ -3,36 +3,118 @@ package app
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/core-go/health"
 	h "github.com/core-go/health/sql"
 	"github.com/core-go/log/zap"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"go-service/internal/storage"
 	"go-service/internal/user"
+	"go-service/internal/user/graph"
+	"go-service/internal/user/service"
+	"go-service/internal/webhook"
 )
 
 type ApplicationContext struct {
-	Health *health.Handler
-	User   user.UserTransport
+	Health  *health.Handler
+	User    user.UserTransport
+	Graph   *graph.Handler
+	Webhook *webhook.Handler
 }
 
 func NewApp(ctx context.Context, cfg Config) (*ApplicationContext, error) {
-	db, err := sql.Open(cfg.Sql.Driver, cfg.Sql.DataSourceName)
+	userStorage, err := newStorage(ctx, cfg.Storage)
 	if err != nil {
 		return nil, err
 	}
+	if err := userStorage.Migrate(ctx); err != nil {
+		return nil, err
+	}
 	logError := log.LogError
 
-	userHandler, err := user.NewUserHandler(db, logError, cfg.Action)
+	outbox, webhookHandler, err := newWebhook(ctx, userStorage, cfg.Webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	userHandler, err := user.NewUserHandler(userStorage, outbox, logError, cfg.Action)
 	if err != nil {
 		return nil, err
 	}
 
-	sqlChecker := h.NewHealthChecker(db)
-	healthHandler := health.NewHandler(sqlChecker)
+	userGraphHandler, err := user.NewUserGraphHandler(userStorage, outbox)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkers []health.Checker
+	if sqlStorage, ok := userStorage.(*storage.SqlStorage); ok {
+		checkers = append(checkers, h.NewHealthChecker(sqlStorage.DB))
+	}
+	healthHandler := health.NewHandler(checkers...)
 
 	return &ApplicationContext{
-		Health: healthHandler,
-		User:   userHandler,
+		Health:  healthHandler,
+		User:    userHandler,
+		Graph:   userGraphHandler,
+		Webhook: webhookHandler,
 	}, nil
 }
+
+// newWebhook sets up the outbox-backed webhook subsystem when userStorage is a
+// database/sql connection: it migrates the outbox/subscriber tables, starts a
+// Dispatcher polling in the background for the lifetime of ctx, and returns
+// the service.OutboxRecorder for user.NewUserHandler/NewUserGraphHandler to
+// record lifecycle events through. Other storage kinds return a nil recorder,
+// and the service skips outbox recording entirely.
+func newWebhook(ctx context.Context, userStorage storage.Storage, cfg WebhookConfig) (service.OutboxRecorder, *webhook.Handler, error) {
+	sqlStorage, ok := userStorage.(*storage.SqlStorage)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	repository := webhook.NewRepository(sqlStorage.DB)
+	if cfg.MaxRetries > 0 {
+		repository.MaxRetries = cfg.MaxRetries
+	}
+	if err := repository.Migrate(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	dispatcher := webhook.NewDispatcher(repository)
+	if cfg.PollInterval > 0 {
+		dispatcher.PollInterval = cfg.PollInterval
+	}
+	if cfg.BatchSize > 0 {
+		dispatcher.BatchSize = cfg.BatchSize
+	}
+	go dispatcher.Run(ctx)
+
+	return repository, webhook.NewHandler(repository), nil
+}
+
+// newStorage picks the storage.Storage implementation named by cfg.Kind, so the
+// rest of the app is built against the interface rather than *sql.DB directly.
+func newStorage(ctx context.Context, cfg StorageConfig) (storage.Storage, error) {
+	switch storage.Kind(cfg.Kind) {
+	case storage.KindMongo:
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.Mongo.Uri))
+		if err != nil {
+			return nil, err
+		}
+		return storage.NewMongoStorage(client.Database(cfg.Mongo.Database)), nil
+	case storage.KindMemory:
+		return storage.NewMemoryStorage(), nil
+	case storage.KindSql, "":
+		db, err := sql.Open(cfg.Sql.Driver, cfg.Sql.DataSourceName)
+		if err != nil {
+			return nil, err
+		}
+		return storage.NewSqlStorage(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage kind %q", cfg.Kind)
+	}
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"time"
+
+	"github.com/core-go/core"
+)
+
+type SqlConfig struct {
+	Driver         string `mapstructure:"driver"`
+	DataSourceName string `mapstructure:"data_source_name"`
+}
+
+type MongoConfig struct {
+	Uri      string `mapstructure:"uri"`
+	Database string `mapstructure:"database"`
+}
+
+type StorageConfig struct {
+	Kind  string      `mapstructure:"kind"`
+	Sql   SqlConfig   `mapstructure:"sql"`
+	Mongo MongoConfig `mapstructure:"mongo"`
+}
+
+// WebhookConfig tunes the outbox Dispatcher. It only takes effect when
+// Storage.Kind is "sql" (or empty, which defaults to "sql"), since the outbox
+// table the Dispatcher polls lives
+// alongside the users table. Zero values fall back to the Dispatcher's
+// built-in defaults.
+type WebhookConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	MaxRetries   int           `mapstructure:"max_retries"`
+}
+
+type Config struct {
+	Storage StorageConfig      `mapstructure:"storage"`
+	Webhook WebhookConfig      `mapstructure:"webhook"`
+	Action  *core.ActionConfig `mapstructure:"action"`
+}
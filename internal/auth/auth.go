@@ -0,0 +1,117 @@
+// Package auth carries the caller's identity and roles on a request context,
+// so handlers and repositories can make field-level authorization and row
+// filtering decisions without threading an extra parameter through every
+// service and repository method.
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Principal identifies the caller making a request. A nil *Principal means
+// no authentication middleware populated the context - HasAnyRole,
+// CanAccessRow and IsFieldAuthorized treat that as "the authorization feature
+// isn't engaged" and default to full access, so existing callers are
+// unaffected until they wire Middleware in. HasRole alone reports false for a
+// nil principal, since it holds no roles.
+type Principal struct {
+	Id    string
+	Roles []string
+}
+
+// HasRole reports whether p was granted role. A nil principal has no roles.
+func (p *Principal) HasRole(role string) bool {
+	if p == nil {
+		return false
+	}
+	for _, have := range p.Roles {
+		if have == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnyRole reports whether p was granted at least one of roles. A nil
+// principal is treated as fully privileged, since no auth middleware is
+// configured.
+func (p *Principal) HasAnyRole(roles ...string) bool {
+	if p == nil {
+		return true
+	}
+	for _, role := range roles {
+		if p.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessRow reports whether p may view the row owned by id: admins can
+// view any row, and so can a nil principal; everyone else can only view
+// their own.
+func (p *Principal) CanAccessRow(id string) bool {
+	if p == nil || p.HasRole("admin") {
+		return true
+	}
+	return p.Id == id
+}
+
+// IsFieldAuthorized reports whether principal may see a field gated behind
+// roles. "owner" is a special role meaning "the caller's own row" - it's
+// satisfied by principal.Id matching ownerId rather than by literally
+// holding a role named "owner". A nil principal means no auth middleware is
+// configured, so it's treated as fully privileged like everywhere else on
+// Principal.
+func IsFieldAuthorized(principal *Principal, roles []string, ownerId string) bool {
+	if principal == nil {
+		return true
+	}
+	for _, role := range roles {
+		if role == "owner" {
+			if principal.Id == ownerId {
+				return true
+			}
+			continue
+		}
+		if principal.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// FromContext returns the Principal carried by ctx, or nil if none was set.
+func FromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(contextKey{}).(*Principal)
+	return principal
+}
+
+// Middleware populates the request context with a Principal read from the
+// X-User-Id and X-Roles headers. It stands in for whatever real
+// authentication (JWT, session cookie, a gateway-injected header, ...) sits
+// in front of this service in production.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-User-Id")
+		if id == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		var roles []string
+		if raw := r.Header.Get("X-Roles"); raw != "" {
+			roles = strings.Split(raw, ",")
+		}
+		ctx := WithPrincipal(r.Context(), &Principal{Id: id, Roles: roles})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
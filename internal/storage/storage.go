@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Kind string
+
+const (
+	KindSql    Kind = "sql"
+	KindMongo  Kind = "mongo"
+	KindMemory Kind = "memory"
+)
+
+// Storage abstracts the persistence technology backing the user service so it
+// isn't hard-coded to database/sql. app.NewApp picks an implementation from
+// cfg.Storage.Kind and builds the rest of the stack against this interface.
+type Storage interface {
+	Kind() Kind
+	Migrate(ctx context.Context) error
+}
+
+type SqlStorage struct {
+	DB *sql.DB
+}
+
+func NewSqlStorage(db *sql.DB) *SqlStorage {
+	return &SqlStorage{DB: db}
+}
+
+func (s *SqlStorage) Kind() Kind { return KindSql }
+
+func (s *SqlStorage) Migrate(ctx context.Context) error {
+	_, err := s.DB.ExecContext(ctx, `create table if not exists users (
+		id varchar(40) primary key,
+		username varchar(100) not null,
+		email varchar(100) not null,
+		phone varchar(18) not null,
+		dateofbirth timestamp,
+		version bigint not null default 0
+	)`)
+	return err
+}
+
+type MongoStorage struct {
+	Database *mongo.Database
+}
+
+func NewMongoStorage(db *mongo.Database) *MongoStorage {
+	return &MongoStorage{Database: db}
+}
+
+func (s *MongoStorage) Kind() Kind { return KindMongo }
+
+func (s *MongoStorage) Migrate(ctx context.Context) error {
+	names, err := s.Database.ListCollectionNames(ctx, bson.M{"name": "users"})
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return nil
+	}
+	return s.Database.CreateCollection(ctx, "users")
+}
+
+// MemoryStorage backs the in-memory fake repository used for handler tests
+// that run against httptest without spinning up a database.
+type MemoryStorage struct{}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{}
+}
+
+func (s *MemoryStorage) Kind() Kind { return KindMemory }
+
+func (s *MemoryStorage) Migrate(ctx context.Context) error { return nil }
@@ -0,0 +1,72 @@
+// Package authz applies model.User's field-level `roles:"..."` authorization
+// tags to a fetched row, so every transport - REST, GraphQL, CSV/NDJSON
+// export - redacts the same fields for the same callers instead of each one
+// reimplementing the policy.
+package authz
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"go-service/internal/auth"
+	"go-service/internal/user/model"
+)
+
+// restrictedFields maps every model.User json field carrying a `roles`
+// struct tag to the roles allowed to see it, e.g. `roles:"admin,owner"` on
+// Email and Phone. Built once from the struct tags so a future tagged field
+// is picked up automatically.
+var restrictedFields = buildRestrictedFields()
+
+func buildRestrictedFields() map[string][]string {
+	userType := reflect.TypeOf(model.User{})
+	restricted := make(map[string][]string)
+	for i := 0; i < userType.NumField(); i++ {
+		field := userType.Field(i)
+		tag := field.Tag.Get("roles")
+		if tag == "" {
+			continue
+		}
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		restricted[jsonName] = strings.Split(tag, ",")
+	}
+	return restricted
+}
+
+// MaskUser re-encodes user as a generic map with every field principal isn't
+// authorized to see, per restrictedFields, replaced with null - rather than
+// its string zero value - so an unprivileged caller can tell "redacted" apart
+// from "actually empty".
+func MaskUser(principal *auth.Principal, user *model.User) (map[string]interface{}, error) {
+	if user == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for jsonName, roles := range restrictedFields {
+		if !auth.IsFieldAuthorized(principal, roles, user.Id) {
+			fields[jsonName] = nil
+		}
+	}
+	return fields, nil
+}
+
+// MaskUsers applies MaskUser to every row of users.
+func MaskUsers(principal *auth.Principal, users []model.User) ([]map[string]interface{}, error) {
+	masked := make([]map[string]interface{}, len(users))
+	for i := range users {
+		fields, err := MaskUser(principal, &users[i])
+		if err != nil {
+			return nil, err
+		}
+		masked[i] = fields
+	}
+	return masked, nil
+}
@@ -0,0 +1,86 @@
+package authz
+
+import (
+	"testing"
+
+	"go-service/internal/auth"
+	"go-service/internal/user/model"
+)
+
+func TestMaskUser(t *testing.T) {
+	user := &model.User{Id: "u1", Username: "alice", Email: "alice@example.com", Phone: "555-0100"}
+
+	t.Run("unprivileged caller sees restricted fields redacted", func(t *testing.T) {
+		principal := &auth.Principal{Id: "u2"}
+		masked, err := MaskUser(principal, user)
+		if err != nil {
+			t.Fatalf("MaskUser returned error: %v", err)
+		}
+		if masked["email"] != nil {
+			t.Errorf("email = %v, want nil", masked["email"])
+		}
+		if masked["phone"] != nil {
+			t.Errorf("phone = %v, want nil", masked["phone"])
+		}
+		if masked["username"] != "alice" {
+			t.Errorf("username = %v, want %q", masked["username"], "alice")
+		}
+	})
+
+	t.Run("owner sees their own restricted fields", func(t *testing.T) {
+		principal := &auth.Principal{Id: "u1"}
+		masked, err := MaskUser(principal, user)
+		if err != nil {
+			t.Fatalf("MaskUser returned error: %v", err)
+		}
+		if masked["email"] != "alice@example.com" {
+			t.Errorf("email = %v, want %q", masked["email"], "alice@example.com")
+		}
+	})
+
+	t.Run("admin sees the full record", func(t *testing.T) {
+		principal := &auth.Principal{Id: "u2", Roles: []string{"admin"}}
+		masked, err := MaskUser(principal, user)
+		if err != nil {
+			t.Fatalf("MaskUser returned error: %v", err)
+		}
+		if masked["email"] != "alice@example.com" {
+			t.Errorf("email = %v, want %q", masked["email"], "alice@example.com")
+		}
+		if masked["phone"] != "555-0100" {
+			t.Errorf("phone = %v, want %q", masked["phone"], "555-0100")
+		}
+	})
+
+	t.Run("nil principal is treated as fully privileged", func(t *testing.T) {
+		masked, err := MaskUser(nil, user)
+		if err != nil {
+			t.Fatalf("MaskUser returned error: %v", err)
+		}
+		if masked["email"] != "alice@example.com" {
+			t.Errorf("email = %v, want %q", masked["email"], "alice@example.com")
+		}
+	})
+}
+
+func TestMaskUsers(t *testing.T) {
+	users := []model.User{
+		{Id: "u1", Username: "alice", Email: "alice@example.com"},
+		{Id: "u2", Username: "bob", Email: "bob@example.com"},
+	}
+	principal := &auth.Principal{Id: "u1"}
+
+	masked, err := MaskUsers(principal, users)
+	if err != nil {
+		t.Fatalf("MaskUsers returned error: %v", err)
+	}
+	if len(masked) != 2 {
+		t.Fatalf("len(masked) = %d, want 2", len(masked))
+	}
+	if masked[0]["email"] != "alice@example.com" {
+		t.Errorf("masked[0][email] = %v, want owner's email unmasked", masked[0]["email"])
+	}
+	if masked[1]["email"] != nil {
+		t.Errorf("masked[1][email] = %v, want nil (not the owner's row)", masked[1]["email"])
+	}
+}
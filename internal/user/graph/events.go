@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"go-service/internal/user/model"
+)
+
+type Event struct {
+	Type string      `json:"type"`
+	User *model.User `json:"user"`
+}
+
+// EventBroker fans mutation events out to the subscribers of the userEvents
+// subscription. A slow or gone subscriber never blocks a publish.
+type EventBroker struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+func NewEventBroker() *EventBroker {
+	return &EventBroker{subs: make(map[chan Event]struct{})}
+}
+
+func (b *EventBroker) Subscribe(ctx context.Context) <-chan Event {
+	ch := make(chan Event, 8)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+
+	return ch
+}
+
+func (b *EventBroker) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
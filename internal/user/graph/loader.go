@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-service/internal/user/model"
+	"go-service/internal/user/service"
+)
+
+type loaderKey struct{}
+
+type loaderResult struct {
+	user *model.User
+	err  error
+}
+
+// Loader is a request-scoped DataLoader: it coalesces concurrent Load(ctx, id)
+// calls made while resolving a single GraphQL request into one
+// service.UserService.LoadMany call, which storages that support it (e.g. the
+// SQL UserAdapter) turn into a single `SELECT ... WHERE id IN (...)`.
+type Loader struct {
+	service service.UserService
+	wait    time.Duration
+
+	mu      sync.Mutex
+	pending map[string][]chan loaderResult
+	timer   *time.Timer
+}
+
+func NewLoader(userService service.UserService) *Loader {
+	return &Loader{
+		service: userService,
+		wait:    time.Millisecond,
+		pending: make(map[string][]chan loaderResult),
+	}
+}
+
+func WithLoader(ctx context.Context, loader *Loader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, loader)
+}
+
+func LoaderFromContext(ctx context.Context) *Loader {
+	loader, _ := ctx.Value(loaderKey{}).(*Loader)
+	return loader
+}
+
+func (l *Loader) Load(ctx context.Context, id string) (*model.User, error) {
+	ch := make(chan loaderResult, 1)
+
+	l.mu.Lock()
+	l.pending[id] = append(l.pending[id], ch)
+	if l.timer == nil {
+		l.timer = time.AfterFunc(l.wait, func() { l.dispatch(ctx) })
+	}
+	l.mu.Unlock()
+
+	res := <-ch
+	return res.user, res.err
+}
+
+func (l *Loader) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = make(map[string][]chan loaderResult)
+	l.timer = nil
+	l.mu.Unlock()
+
+	ids := make([]string, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+
+	users, err := l.service.LoadMany(ctx, ids)
+	byID := make(map[string]*model.User, len(users))
+	for i := range users {
+		byID[users[i].Id] = &users[i]
+	}
+
+	for id, waiters := range pending {
+		res := loaderResult{err: err}
+		if err == nil {
+			res.user = byID[id]
+		}
+		for _, ch := range waiters {
+			ch <- res
+			close(ch)
+		}
+	}
+}
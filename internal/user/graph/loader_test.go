@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go-service/internal/user/model"
+)
+
+// loadManyRecorder is a minimal service.UserService stub that just records how
+// many times LoadMany was called and with which ids, so the test can assert
+// the Loader actually coalesces concurrent Load calls into one round trip.
+type loadManyRecorder struct {
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (s *loadManyRecorder) All(ctx context.Context) ([]model.User, error) { return nil, nil }
+func (s *loadManyRecorder) Load(ctx context.Context, id string) (*model.User, error) {
+	return nil, nil
+}
+func (s *loadManyRecorder) Create(ctx context.Context, user *model.User) (int64, error) {
+	return 0, nil
+}
+func (s *loadManyRecorder) Update(ctx context.Context, user *model.User) (int64, error) {
+	return 0, nil
+}
+func (s *loadManyRecorder) Patch(ctx context.Context, user map[string]interface{}) (int64, error) {
+	return 0, nil
+}
+func (s *loadManyRecorder) Delete(ctx context.Context, id string) (int64, error) { return 0, nil }
+func (s *loadManyRecorder) Search(ctx context.Context, filter *model.UserFilter, limit int64, offset int64) ([]model.User, int64, error) {
+	return nil, 0, nil
+}
+func (s *loadManyRecorder) Import(ctx context.Context, users []model.User) ([]int64, []error) {
+	return nil, nil
+}
+func (s *loadManyRecorder) Export(ctx context.Context, filter *model.UserFilter, fn func(*model.User) error) error {
+	return nil
+}
+func (s *loadManyRecorder) LoadMany(ctx context.Context, ids []string) ([]model.User, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, append([]string(nil), ids...))
+	s.mu.Unlock()
+
+	users := make([]model.User, len(ids))
+	for i, id := range ids {
+		users[i] = model.User{Id: id, Username: "user-" + id}
+	}
+	return users, nil
+}
+
+func TestLoader_CoalescesConcurrentLoads(t *testing.T) {
+	recorder := &loadManyRecorder{}
+	loader := NewLoader(recorder)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	ids := []string{"u1", "u2", "u3"}
+	results := make([]*model.User, len(ids))
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			user, err := loader.Load(ctx, id)
+			if err != nil {
+				t.Errorf("Load(%s): %v", id, err)
+				return
+			}
+			results[i] = user
+		}(i, id)
+	}
+	wg.Wait()
+
+	recorder.mu.Lock()
+	calls := len(recorder.calls)
+	recorder.mu.Unlock()
+	if calls != 1 {
+		t.Fatalf("LoadMany called %d times, want 1 call for %d concurrent Loads", calls, len(ids))
+	}
+
+	for i, id := range ids {
+		if results[i] == nil || results[i].Id != id {
+			t.Fatalf("Load(%s) = %+v, want a user with that id", id, results[i])
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/core-go/search"
+
+	"go-service/internal/auth"
+	"go-service/internal/user/authz"
+	"go-service/internal/user/model"
+	"go-service/internal/user/service"
+)
+
+// Resolver exposes the same CRUD/search surface as handler.UserHandler through
+// the GraphQL transport, reusing service.UserService so business logic lives
+// in one place regardless of which transport a client talks to - including
+// the auth.Principal row/field access rules handler.UserHandler enforces.
+type Resolver struct {
+	service service.UserService
+	events  *EventBroker
+}
+
+func NewResolver(service service.UserService, events *EventBroker) *Resolver {
+	return &Resolver{service: service, events: events}
+}
+
+func (r *Resolver) User(ctx context.Context, id string) (map[string]interface{}, error) {
+	principal := auth.FromContext(ctx)
+	if !principal.CanAccessRow(id) {
+		return nil, nil
+	}
+	user, err := LoaderFromContext(ctx).Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return authz.MaskUser(principal, user)
+}
+
+func (r *Resolver) Users(ctx context.Context, filter *model.UserFilter, limit int64, offset int64) (*search.Result, error) {
+	// Row filter: non-admins only ever search their own record, regardless of
+	// what id they passed in - same rule as handler.UserHandler.Search.
+	principal := auth.FromContext(ctx)
+	if principal != nil && !principal.HasRole("admin") {
+		filter.Id = principal.Id
+	}
+
+	users, total, err := r.service.Search(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	masked, err := authz.MaskUsers(principal, users)
+	if err != nil {
+		return nil, err
+	}
+	return &search.Result{List: &masked, Total: total}, nil
+}
+
+func (r *Resolver) CreateUser(ctx context.Context, input model.User) (map[string]interface{}, error) {
+	if _, err := r.service.Create(ctx, &input); err != nil {
+		return nil, err
+	}
+	r.events.Publish(Event{Type: "created", User: &input})
+	return authz.MaskUser(auth.FromContext(ctx), &input)
+}
+
+func (r *Resolver) UpdateUser(ctx context.Context, input model.User) (map[string]interface{}, error) {
+	principal := auth.FromContext(ctx)
+	if !principal.CanAccessRow(input.Id) {
+		return nil, nil
+	}
+	if _, err := r.service.Update(ctx, &input); err != nil {
+		return nil, err
+	}
+	r.events.Publish(Event{Type: "updated", User: &input})
+	return authz.MaskUser(principal, &input)
+}
+
+func (r *Resolver) PatchUser(ctx context.Context, id string, patch map[string]interface{}) (map[string]interface{}, error) {
+	principal := auth.FromContext(ctx)
+	if !principal.CanAccessRow(id) {
+		return nil, nil
+	}
+	patch["id"] = id
+	if _, err := r.service.Patch(ctx, patch); err != nil {
+		return nil, err
+	}
+	user, err := r.service.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.events.Publish(Event{Type: "patched", User: user})
+	return authz.MaskUser(principal, user)
+}
+
+func (r *Resolver) DeleteUser(ctx context.Context, id string) (bool, error) {
+	if !auth.FromContext(ctx).CanAccessRow(id) {
+		return false, nil
+	}
+	affected, err := r.service.Delete(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if affected > 0 {
+		r.events.Publish(Event{Type: "deleted", User: &model.User{Id: id}})
+	}
+	return affected > 0, nil
+}
+
+func (r *Resolver) UserEvents(ctx context.Context) (<-chan Event, error) {
+	return r.events.Subscribe(ctx), nil
+}
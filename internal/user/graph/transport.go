@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/core-go/core"
+	"github.com/core-go/search"
+
+	"go-service/internal/user/model"
+	"go-service/internal/user/service"
+)
+
+type Request struct {
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []string    `json:"errors,omitempty"`
+}
+
+// Handler is the GraphQL transport, sitting alongside handler.UserHandler and
+// backed by the same Resolver. Every request gets a fresh Loader so sibling
+// field resolutions batch into one query instead of N.
+type Handler struct {
+	resolver *Resolver
+	service  service.UserService
+}
+
+func NewHandler(resolver *Resolver, userService service.UserService) *Handler {
+	return &Handler{resolver: resolver, service: userService}
+}
+
+// Subscribe serves the userEvents subscription. Unlike dispatch's
+// request/response operations, a subscription is a long-lived stream, so it
+// gets its own route and NDJSON framing - one Event per line, flushed as
+// soon as it's published - instead of a single JSON response.
+func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	events, err := h.resolver.UserEvents(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := enc.Encode(event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := WithLoader(r.Context(), NewLoader(h.service))
+	data, err := h.dispatch(ctx, req)
+	if err != nil {
+		core.JSON(w, http.StatusOK, Response{Errors: []string{err.Error()}})
+		return
+	}
+	core.JSON(w, http.StatusOK, Response{Data: data})
+}
+
+func (h *Handler) dispatch(ctx context.Context, req Request) (interface{}, error) {
+	switch req.OperationName {
+	case "user":
+		id, _ := req.Variables["id"].(string)
+		return h.resolver.User(ctx, id)
+	case "users":
+		filter := decodeFilter(req.Variables["filter"])
+		limit := intVar(req.Variables["limit"])
+		offset := intVar(req.Variables["offset"])
+		return h.resolver.Users(ctx, filter, limit, offset)
+	case "createUser":
+		var input model.User
+		if err := decodeVar(req.Variables["input"], &input); err != nil {
+			return nil, err
+		}
+		return h.resolver.CreateUser(ctx, input)
+	case "updateUser":
+		var input model.User
+		if err := decodeVar(req.Variables["input"], &input); err != nil {
+			return nil, err
+		}
+		return h.resolver.UpdateUser(ctx, input)
+	case "patchUser":
+		id, _ := req.Variables["id"].(string)
+		var patch map[string]interface{}
+		switch v := req.Variables["patch"].(type) {
+		case string:
+			// schema.graphqls declares patch as String!: a JSON-encoded object.
+			if err := json.Unmarshal([]byte(v), &patch); err != nil {
+				return nil, err
+			}
+		case map[string]interface{}:
+			patch = v
+		}
+		if patch == nil {
+			return nil, fmt.Errorf("patch must be a JSON object")
+		}
+		return h.resolver.PatchUser(ctx, id, patch)
+	case "deleteUser":
+		id, _ := req.Variables["id"].(string)
+		return h.resolver.DeleteUser(ctx, id)
+	case "userEvents":
+		return nil, fmt.Errorf("userEvents is a subscription, not a request/response operation - use the streaming Subscribe route instead")
+	default:
+		return nil, fmt.Errorf("unknown operation %q", req.OperationName)
+	}
+}
+
+func decodeVar(v interface{}, dst interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+func decodeFilter(v interface{}) *model.UserFilter {
+	filter := &model.UserFilter{Filter: &search.Filter{}}
+	_ = decodeVar(v, filter)
+	return filter
+}
+
+func intVar(v interface{}) int64 {
+	n, _ := v.(float64)
+	return int64(n)
+}
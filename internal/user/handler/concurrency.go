@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-service/internal/user/model"
+)
+
+// ConflictResponse is the body written with 409 Conflict when Update or Patch
+// loses an optimistic-concurrency race, so the client knows what version to
+// re-read before retrying.
+type ConflictResponse struct {
+	Error          string `json:"error"`
+	CurrentVersion int64  `json:"currentVersion,omitempty"`
+}
+
+// versionFromIfMatch is a companion to core.DecodeAndCheckId for clients that
+// send the version they read via the If-Match header instead of embedding it
+// in the request body.
+func versionFromIfMatch(r *http.Request) (int64, bool) {
+	raw := strings.Trim(r.Header.Get("If-Match"), `"`)
+	if raw == "" {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
+// handleStaleWrite reports a 409 Conflict carrying the row's current version
+// when err is model.ErrStaleWrite. It returns true when it has written the
+// response and the caller should stop.
+func (h *UserHandler) handleStaleWrite(w http.ResponseWriter, r *http.Request, id string, err error) bool {
+	if err != model.ErrStaleWrite {
+		return false
+	}
+	body := ConflictResponse{Error: "version mismatch"}
+	if current, loadErr := h.service.Load(r.Context(), id); loadErr == nil && current != nil {
+		body.CurrentVersion = current.Version
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	_ = json.NewEncoder(w).Encode(body)
+	return true
+}
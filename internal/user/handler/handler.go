@@ -8,6 +8,8 @@ import (
 	"github.com/core-go/core"
 	"github.com/core-go/search"
 
+	"go-service/internal/auth"
+	"go-service/internal/user/authz"
 	"go-service/internal/user/model"
 	"go-service/internal/user/service"
 )
@@ -33,18 +35,40 @@ func (h *UserHandler) All(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	core.JSON(w, http.StatusOK, users)
+	principal := auth.FromContext(r.Context())
+	masked, err := authz.MaskUsers(principal, users)
+	if err != nil {
+		h.Error(r.Context(), fmt.Sprintf("Error: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	core.JSON(w, http.StatusOK, masked)
 }
 func (h *UserHandler) Load(w http.ResponseWriter, r *http.Request) {
 	id, err := core.GetRequiredString(w, r)
 	if err == nil {
+		principal := auth.FromContext(r.Context())
+		if !principal.CanAccessRow(id) {
+			core.JSON(w, http.StatusNotFound, nil)
+			return
+		}
 		user, err := h.service.Load(r.Context(), id)
 		if err != nil {
 			h.Error(r.Context(), fmt.Sprintf("Error to get user '%s': %s", id, err.Error()))
 			http.Error(w, core.InternalServerError, http.StatusInternalServerError)
 			return
 		}
-		core.JSON(w, core.IsFound(user), user)
+		if user == nil {
+			core.JSON(w, http.StatusNotFound, user)
+			return
+		}
+		masked, err := authz.MaskUser(principal, user)
+		if err != nil {
+			h.Error(r.Context(), fmt.Sprintf("Error to get user '%s': %s", id, err.Error()))
+			http.Error(w, core.InternalServerError, http.StatusInternalServerError)
+			return
+		}
+		core.JSON(w, http.StatusOK, masked)
 	}
 }
 func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -60,9 +84,19 @@ func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 	user, er1 := core.DecodeAndCheckId[model.User](w, r, h.Keys, h.Indexes)
 	if er1 == nil {
+		if !auth.FromContext(r.Context()).CanAccessRow(user.Id) {
+			core.JSON(w, http.StatusNotFound, nil)
+			return
+		}
+		if version, ok := versionFromIfMatch(r); ok {
+			user.Version = version
+		}
 		errors, er2 := h.Validate(r.Context(), &user)
 		if !core.HasError(w, r, errors, er2, h.Error, user, h.Log, h.Resource, h.Action.Update) {
 			res, er3 := h.service.Update(r.Context(), &user)
+			if h.handleStaleWrite(w, r, user.Id, er3) {
+				return
+			}
 			core.AfterSaved(w, r, &user, res, er3, h.Error)
 		}
 	}
@@ -70,9 +104,19 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandler) Patch(w http.ResponseWriter, r *http.Request) {
 	r, user, jsonUser, er1 := core.BuildMapAndCheckId[model.User](w, r, h.Keys, h.Indexes)
 	if er1 == nil {
+		if !auth.FromContext(r.Context()).CanAccessRow(user.Id) {
+			core.JSON(w, http.StatusNotFound, nil)
+			return
+		}
+		if version, ok := versionFromIfMatch(r); ok {
+			jsonUser["version"] = version
+		}
 		errors, er2 := h.Validate(r.Context(), &user)
 		if !core.HasError(w, r, errors, er2, h.Error, jsonUser, h.Log, h.Resource, h.Action.Patch) {
 			res, er3 := h.service.Patch(r.Context(), jsonUser)
+			if h.handleStaleWrite(w, r, user.Id, er3) {
+				return
+			}
 			core.AfterSaved(w, r, jsonUser, res, er3, h.Error)
 		}
 	}
@@ -80,6 +124,10 @@ func (h *UserHandler) Patch(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id, err := core.GetRequiredString(w, r)
 	if err == nil {
+		if !auth.FromContext(r.Context()).CanAccessRow(id) {
+			core.JSON(w, http.StatusNotFound, nil)
+			return
+		}
 		res, err := h.service.Delete(r.Context(), id)
 		core.AfterDeleted(w, r, res, err, h.Error)
 	}
@@ -92,11 +140,23 @@ func (h *UserHandler) Search(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Row filter: non-admins only ever search their own record, regardless of
+	// what id they passed in.
+	principal := auth.FromContext(r.Context())
+	if principal != nil && !principal.HasRole("admin") {
+		filter.Id = principal.Id
+	}
+
 	offset := search.GetOffset(filter.Limit, filter.Page)
 	users, total, err := h.service.Search(r.Context(), &filter, filter.Limit, offset)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	core.JSON(w, http.StatusOK, &search.Result{List: &users, Total: total})
+	masked, err := authz.MaskUsers(principal, users)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	core.JSON(w, http.StatusOK, &search.Result{List: &masked, Total: total})
 }
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/core-go/search"
+
+	"go-service/internal/auth"
+	"go-service/internal/user/authz"
+	"go-service/internal/user/model"
+)
+
+const defaultImportBatchSize = 100
+
+type ImportRowResult struct {
+	Line   int    `json:"line"`
+	Id     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type userRow struct {
+	user model.User
+	err  error
+}
+
+// Import stream-decodes CSV or NDJSON rows, validates each one and inserts them in
+// batches, writing an NDJSON report so a client can resume on failures. A
+// `?dry-run=true` query param runs validation only, without touching the database.
+func (h *UserHandler) Import(w http.ResponseWriter, r *http.Request) {
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, "invalid Content-Type", http.StatusBadRequest)
+		return
+	}
+
+	var rows <-chan userRow
+	switch contentType {
+	case "text/csv":
+		rows = decodeCSVRows(r.Body)
+	case "application/x-ndjson":
+		rows = decodeNDJSONRows(r.Body)
+	default:
+		http.Error(w, "unsupported Content-Type, expected text/csv or application/x-ndjson", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry-run") == "true"
+	batchSize := defaultImportBatchSize
+	if raw := r.URL.Query().Get("batch-size"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			batchSize = n
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	report := func(res ImportRowResult) {
+		_ = enc.Encode(res)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	batch := make([]model.User, 0, batchSize)
+	lines := make([]int, 0, batchSize)
+	flushBatch := func() {
+		if len(batch) == 0 {
+			return
+		}
+		for _, res := range h.importBatch(r.Context(), batch, lines, dryRun) {
+			report(res)
+		}
+		batch = batch[:0]
+		lines = lines[:0]
+	}
+
+	line := 0
+	for row := range rows {
+		line++
+		if row.err != nil {
+			report(ImportRowResult{Line: line, Status: "error", Error: row.err.Error()})
+			continue
+		}
+		errs, err := h.Validate(r.Context(), &row.user)
+		if err != nil {
+			report(ImportRowResult{Line: line, Id: row.user.Id, Status: "error", Error: err.Error()})
+			continue
+		}
+		if len(errs) > 0 {
+			report(ImportRowResult{Line: line, Id: row.user.Id, Status: "error", Error: "validation failed"})
+			continue
+		}
+		batch = append(batch, row.user)
+		lines = append(lines, line)
+		if len(batch) >= batchSize {
+			flushBatch()
+		}
+	}
+	flushBatch()
+}
+
+func (h *UserHandler) importBatch(ctx context.Context, batch []model.User, lines []int, dryRun bool) []ImportRowResult {
+	results := make([]ImportRowResult, len(batch))
+	if dryRun {
+		for i := range batch {
+			results[i] = ImportRowResult{Line: lines[i], Id: batch[i].Id, Status: "valid"}
+		}
+		return results
+	}
+	affected, errs := h.service.Import(ctx, batch)
+	for i := range batch {
+		res := ImportRowResult{Line: lines[i], Id: batch[i].Id}
+		switch {
+		case errs[i] != nil:
+			res.Status = "error"
+			res.Error = errs[i].Error()
+		case i < len(affected) && affected[i] > 0:
+			res.Status = "imported"
+		default:
+			res.Status = "error"
+			res.Error = "no rows inserted"
+		}
+		results[i] = res
+	}
+	return results
+}
+
+// Export streams the users table - or a UserFilter-narrowed subset re-using
+// BuildQuery - as CSV or NDJSON, one row at a time, so large tables don't blow
+// the heap. Row filtering and field masking follow the same rule as Search:
+// non-admins only ever export their own record, and restricted columns are
+// redacted the same way they are everywhere else.
+func (h *UserHandler) Export(w http.ResponseWriter, r *http.Request) {
+	filter := model.UserFilter{Filter: &search.Filter{}}
+	if err := search.Decode(r, &filter, h.ParamIndex, h.FilterIndex); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	principal := auth.FromContext(r.Context())
+	if principal != nil && !principal.HasRole("admin") {
+		filter.Id = principal.Id
+	}
+
+	ndjson := r.URL.Query().Get("format") == "ndjson" || r.Header.Get("Accept") == "application/x-ndjson"
+	if ndjson {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		flusher, _ := w.(http.Flusher)
+		enc := json.NewEncoder(w)
+		err := h.service.Export(r.Context(), &filter, func(user *model.User) error {
+			masked, err := authz.MaskUser(principal, user)
+			if err != nil {
+				return err
+			}
+			if err := enc.Encode(masked); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			h.Error(r.Context(), "Error exporting users: "+err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	writer := csv.NewWriter(w)
+	header := []string{"id", "username", "email", "phone", "dateOfBirth"}
+	_ = writer.Write(header)
+	err := h.service.Export(r.Context(), &filter, func(user *model.User) error {
+		masked, err := authz.MaskUser(principal, user)
+		if err != nil {
+			return err
+		}
+		dob := ""
+		if user.DateOfBirth != nil {
+			dob = user.DateOfBirth.Format("2006-01-02")
+		}
+		row := []string{user.Id, maskedString(masked, "username"), maskedString(masked, "email"), maskedString(masked, "phone"), dob}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+		writer.Flush()
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return writer.Error()
+	})
+	if err != nil {
+		h.Error(r.Context(), "Error exporting users: "+err.Error())
+	}
+}
+
+// maskedString reads a string column back out of a MaskUser result, CSV's
+// closest equivalent to JSON null being an empty field.
+func maskedString(masked map[string]interface{}, key string) string {
+	s, _ := masked[key].(string)
+	return s
+}
+
+func decodeCSVRows(body io.ReadCloser) <-chan userRow {
+	out := make(chan userRow)
+	go func() {
+		defer body.Close()
+		defer close(out)
+		reader := csv.NewReader(bufio.NewReader(body))
+		header, err := reader.Read()
+		if err != nil {
+			if err != io.EOF {
+				out <- userRow{err: err}
+			}
+			return
+		}
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				out <- userRow{err: err}
+				continue
+			}
+			fields := make(map[string]interface{}, len(header))
+			for i, col := range header {
+				if i < len(record) {
+					fields[col] = record[i]
+				}
+			}
+			data, err := json.Marshal(fields)
+			if err != nil {
+				out <- userRow{err: err}
+				continue
+			}
+			var user model.User
+			if err := json.Unmarshal(data, &user); err != nil {
+				out <- userRow{err: err}
+				continue
+			}
+			out <- userRow{user: user}
+		}
+	}()
+	return out
+}
+
+func decodeNDJSONRows(body io.ReadCloser) <-chan userRow {
+	out := make(chan userRow)
+	go func() {
+		defer body.Close()
+		defer close(out)
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			var user model.User
+			if err := json.Unmarshal(line, &user); err != nil {
+				out <- userRow{err: err}
+				continue
+			}
+			out <- userRow{user: user}
+		}
+		if err := scanner.Err(); err != nil {
+			out <- userRow{err: err}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/core-go/search"
+)
+
+// ErrStaleWrite is returned by Repository.Update/Patch when the caller's
+// Version no longer matches the row's current version - somebody else wrote
+// it first. handler.UserHandler maps it to HTTP 409 Conflict.
+var ErrStaleWrite = errors.New("stale write: version mismatch")
+
+type User struct {
+	Id          string     `json:"id" gorm:"column:id;primary_key" bson:"_id" dynamodbav:"id" firestore:"-" validate:"required,max=40"`
+	Username    string     `json:"username" gorm:"column:username" bson:"username" dynamodbav:"username" firestore:"username" validate:"required,max=100"`
+	Email       string     `json:"email" gorm:"column:email" bson:"email" dynamodbav:"email" firestore:"email" validate:"required,email,max=100" roles:"admin,owner"`
+	Phone       string     `json:"phone" gorm:"column:phone" bson:"phone" dynamodbav:"phone" firestore:"phone" validate:"required,max=18" roles:"admin,owner"`
+	DateOfBirth *time.Time `json:"dateOfBirth,omitempty" gorm:"column:dateofbirth" bson:"dateOfBirth,omitempty" dynamodbav:"dateOfBirth,omitempty" firestore:"dateOfBirth,omitempty"`
+	Version     int64      `json:"version" gorm:"column:version" bson:"version" dynamodbav:"version" firestore:"version"`
+}
+
+type UserFilter struct {
+	*search.Filter
+	Id       string `json:"id" match:"equal"`
+	Username string `json:"username" match:"prefix"`
+	Email    string `json:"email" match:"prefix"`
+	Phone    string `json:"phone" match:"prefix"`
+}
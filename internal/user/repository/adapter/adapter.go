@@ -6,10 +6,80 @@ import (
 	"fmt"
 	s "github.com/core-go/sql"
 	"reflect"
+	"strings"
 
+	"go-service/internal/auth"
 	"go-service/internal/user/model"
 )
 
+// restrictedColumns maps every gorm column on model.User gated behind a
+// `roles:"..."` struct tag (e.g. email, phone) to the roles allowed to see
+// it - the same tags authz.MaskUser reads, keyed by column instead of json
+// name. fieldsFor uses it to leave a restricted column out of the select
+// list entirely, so Load never even fetches it for a caller who isn't
+// authorized, rather than relying on the handler to mask it after the row
+// has already been read.
+var restrictedColumns = buildRestrictedColumns()
+
+func buildRestrictedColumns() map[string][]string {
+	userType := reflect.TypeOf(model.User{})
+	restricted := make(map[string][]string)
+	for i := 0; i < userType.NumField(); i++ {
+		field := userType.Field(i)
+		tag := field.Tag.Get("roles")
+		if tag == "" {
+			continue
+		}
+		column := gormColumn(field.Tag.Get("gorm"))
+		if column == "" {
+			continue
+		}
+		restricted[column] = strings.Split(tag, ",")
+	}
+	return restricted
+}
+
+// gormColumn extracts the column name out of a `gorm:"column:x;..."` tag.
+func gormColumn(gormTag string) string {
+	for _, part := range strings.Split(gormTag, ";") {
+		if strings.HasPrefix(part, "column:") {
+			return strings.TrimPrefix(part, "column:")
+		}
+	}
+	return ""
+}
+
+// fieldsFor builds the select column list for a row owned by ownerId,
+// leaving out any restrictedColumns entry the caller in ctx isn't
+// authorized to see. It falls back to the full r.Fields when nothing needs
+// excluding, or when excluding would leave no columns at all (a malformed
+// column name in restrictedColumns shouldn't ever produce an empty select).
+func (r *UserAdapter) fieldsFor(ctx context.Context, ownerId string) string {
+	principal := auth.FromContext(ctx)
+	excluded := make(map[string]bool, len(restrictedColumns))
+	for column, roles := range restrictedColumns {
+		if !auth.IsFieldAuthorized(principal, roles, ownerId) {
+			excluded[column] = true
+		}
+	}
+	if len(excluded) == 0 {
+		return r.Fields
+	}
+
+	columns := strings.Split(r.Fields, ",")
+	kept := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if excluded[strings.TrimSpace(column)] {
+			continue
+		}
+		kept = append(kept, column)
+	}
+	if len(kept) == 0 {
+		return r.Fields
+	}
+	return strings.Join(kept, ",")
+}
+
 func NewUserAdapter(db *sql.DB, buildQuery func(*model.UserFilter) (string, []interface{})) (*UserAdapter, error) {
 	userType := reflect.TypeOf(model.User{})
 	parameters, err := s.CreateParameters(userType, db)
@@ -34,7 +104,8 @@ func (r *UserAdapter) All(ctx context.Context) ([]model.User, error) {
 
 func (r *UserAdapter) Load(ctx context.Context, id string) (*model.User, error) {
 	var users []model.User
-	query := fmt.Sprintf("select %s from users where id = %s limit 1", r.Fields, r.BuildParam(1))
+	fields := r.fieldsFor(ctx, id)
+	query := fmt.Sprintf("select %s from users where id = %s limit 1", fields, r.BuildParam(1))
 	err := s.Query(ctx, r.DB, r.Map, &users, query, id)
 	if err != nil {
 		return nil, err
@@ -45,6 +116,29 @@ func (r *UserAdapter) Load(ctx context.Context, id string) (*model.User, error)
 	return nil, nil
 }
 
+// LoadMany fetches several users by id in a single round trip, so callers that
+// coalesce many individual Load calls (e.g. a GraphQL DataLoader) can issue one query.
+// Unlike Load, it doesn't build a restricted field list: the batch can mix ids
+// owned by different principals, so there's no single ownerId to check
+// restrictedColumns against without issuing the whole batch as per-row
+// conditional projections. Callers of LoadMany still need to mask the result
+// themselves, the same as before this field-level authorization existed.
+func (r *UserAdapter) LoadMany(ctx context.Context, ids []string) ([]model.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	params := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		params[i] = r.BuildParam(i + 1)
+		args[i] = id
+	}
+	query := fmt.Sprintf("select %s from users where id in (%s)", r.Fields, strings.Join(params, ","))
+	var users []model.User
+	err := s.Query(ctx, r.DB, r.Map, &users, query, args...)
+	return users, err
+}
+
 func (r *UserAdapter) Create(ctx context.Context, user *model.User) (int64, error) {
 	query, args := s.BuildToInsert("users", user, r.BuildParam)
 	tx := s.GetTx(ctx, r.DB)
@@ -55,25 +149,153 @@ func (r *UserAdapter) Create(ctx context.Context, user *model.User) (int64, erro
 	return res.RowsAffected()
 }
 
+// CreateMany inserts users with a single multi-row INSERT - one round trip
+// for the whole batch instead of one per row - built by hand the same way
+// Update is, since s.BuildToInsert only knows how to build a single-row
+// statement. If the statement fails (e.g. one row collides on id), it falls
+// back to inserting row by row so the caller still finds out which row was
+// the problem, instead of losing every row's result to one opaque batch error.
+func (r *UserAdapter) CreateMany(ctx context.Context, users []model.User) ([]int64, []error) {
+	results := make([]int64, len(users))
+	errs := make([]error, len(users))
+	if len(users) == 0 {
+		return results, errs
+	}
+
+	if err := r.insertMany(ctx, users); err == nil {
+		for i := range users {
+			results[i] = 1
+		}
+		return results, errs
+	}
+
+	for i := range users {
+		affected, err := r.Create(ctx, &users[i])
+		results[i] = affected
+		if err != nil {
+			errs[i] = fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return results, errs
+}
+
+// insertMany builds and runs one "insert into users (...) values (...), (...), ..."
+// statement for the whole batch.
+func (r *UserAdapter) insertMany(ctx context.Context, users []model.User) error {
+	valueGroups := make([]string, len(users))
+	args := make([]interface{}, 0, len(users)*6)
+	n := 1
+	for i, user := range users {
+		params := make([]string, 6)
+		for j := 0; j < 6; j++ {
+			params[j] = r.BuildParam(n)
+			n++
+		}
+		valueGroups[i] = fmt.Sprintf("(%s)", strings.Join(params, ", "))
+		args = append(args, user.Id, user.Username, user.Email, user.Phone, user.DateOfBirth, user.Version)
+	}
+	query := fmt.Sprintf(
+		"insert into users (id, username, email, phone, dateofbirth, version) values %s",
+		strings.Join(valueGroups, ", "),
+	)
+	tx := s.GetTx(ctx, r.DB)
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// Update saves user with an optimistic-concurrency check: the WHERE clause
+// matches both id and the version the caller read, and a successful write
+// bumps version in the same statement. That's why Update builds the query by
+// hand instead of through s.BuildToUpdate - the generic column mapping has no
+// way to express "version = version + 1" or the extra WHERE predicate.
 func (r *UserAdapter) Update(ctx context.Context, user *model.User) (int64, error) {
-	query, args := s.BuildToUpdate("users", user, r.BuildParam)
+	expectedVersion := user.Version
+	query := fmt.Sprintf(
+		"update users set username = %s, email = %s, phone = %s, dateofbirth = %s, version = version + 1 where id = %s and version = %s",
+		r.BuildParam(1), r.BuildParam(2), r.BuildParam(3), r.BuildParam(4), r.BuildParam(5), r.BuildParam(6),
+	)
 	tx := s.GetTx(ctx, r.DB)
-	res, err := tx.ExecContext(ctx, query, args...)
+	res, err := tx.ExecContext(ctx, query, user.Username, user.Email, user.Phone, user.DateOfBirth, user.Id, expectedVersion)
 	if err != nil {
 		return -1, err
 	}
-	return res.RowsAffected()
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+	if affected == 0 {
+		return r.checkStaleWrite(ctx, user.Id)
+	}
+	user.Version = expectedVersion + 1
+	return affected, nil
 }
 
+// Patch applies a partial update with the same optimistic-concurrency check
+// as Update, when the caller included a version. Built by hand for the same
+// reason as Update.
 func (r *UserAdapter) Patch(ctx context.Context, user map[string]interface{}) (int64, error) {
 	colMap := s.JSONToColumns(user, r.JsonColumnMap)
-	query, args := s.BuildToPatch("users", colMap, r.Keys, r.BuildParam)
+	keyColumn := r.Keys[0]
+	id, ok := colMap[keyColumn]
+	if !ok {
+		return 0, fmt.Errorf("missing %s", keyColumn)
+	}
+	expectedVersion, hasVersion := colMap["version"]
+	delete(colMap, keyColumn)
+	delete(colMap, "version")
+
+	sets := make([]string, 0, len(colMap)+1)
+	args := make([]interface{}, 0, len(colMap)+2)
+	i := 1
+	for col, val := range colMap {
+		sets = append(sets, fmt.Sprintf("%s = %s", col, r.BuildParam(i)))
+		args = append(args, val)
+		i++
+	}
+	sets = append(sets, "version = version + 1")
+
+	query := fmt.Sprintf("update users set %s where %s = %s", strings.Join(sets, ", "), keyColumn, r.BuildParam(i))
+	args = append(args, id)
+	i++
+	if hasVersion {
+		query += fmt.Sprintf(" and version = %s", r.BuildParam(i))
+		args = append(args, expectedVersion)
+	}
+
 	tx := s.GetTx(ctx, r.DB)
 	res, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return -1, err
 	}
-	return res.RowsAffected()
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return -1, err
+	}
+	if affected == 0 && hasVersion {
+		return r.checkStaleWrite(ctx, fmt.Sprint(id))
+	}
+	return affected, nil
+}
+
+// checkStaleWrite disambiguates a zero-row UPDATE/PATCH: if id still exists,
+// someone else's write already advanced its version past what the caller
+// expected, so report model.ErrStaleWrite; if it's gone, it's a plain not-found.
+func (r *UserAdapter) checkStaleWrite(ctx context.Context, id string) (int64, error) {
+	existing, err := r.Load(ctx, id)
+	if err != nil {
+		return -1, err
+	}
+	if existing == nil {
+		return 0, nil
+	}
+	return 0, model.ErrStaleWrite
+}
+
+// WithTransaction runs fn with a transaction bound to ctx via s.GetTx, so every
+// repository call fn makes (e.g. a user mutation and its outbox row, in
+// service.mutate) commits or rolls back together.
+func (r *UserAdapter) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.Execute(ctx, r.DB, fn)
 }
 
 func (r *UserAdapter) Delete(ctx context.Context, id string) (int64, error) {
@@ -86,6 +308,13 @@ func (r *UserAdapter) Delete(ctx context.Context, id string) (int64, error) {
 	return res.RowsAffected()
 }
 
+// Search doesn't apply fieldsFor: BuildQuery is handed to this adapter
+// already built by query.UseQuery and selects its own column list, with no
+// hook to override it per call. handler.UserHandler.Search forces filter.Id
+// to the caller's own id for non-admins before it ever reaches here, so in
+// practice every row Search returns to a non-admin is already their own -
+// masking the response after the fetch (authz.MaskUsers) is what actually
+// protects the admin-only columns, the same as it did before this comment.
 func (r *UserAdapter) Search(ctx context.Context, filter *model.UserFilter, limit int64, offset int64) ([]model.User, int64, error) {
 	var users []model.User
 	if limit <= 0 {
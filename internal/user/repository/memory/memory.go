@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-service/internal/user/model"
+)
+
+// UserRepository is an in-memory fake used in place of a real database. It lets
+// handler-level tests exercise httptest.NewServer without spinning one up.
+type UserRepository struct {
+	mu    sync.RWMutex
+	users map[string]model.User
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{users: make(map[string]model.User)}
+}
+
+func (r *UserRepository) All(ctx context.Context) ([]model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	users := make([]model.User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Id < users[j].Id })
+	return users, nil
+}
+
+func (r *UserRepository) Load(ctx context.Context, id string) (*model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if user, ok := r.users[id]; ok {
+		cp := user
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (r *UserRepository) LoadMany(ctx context.Context, ids []string) ([]model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	users := make([]model.User, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *model.User) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.users[user.Id]; exists {
+		return 0, fmt.Errorf("user %q already exists", user.Id)
+	}
+	r.users[user.Id] = *user
+	return 1, nil
+}
+
+// Update replaces the stored user with the same optimistic-concurrency check
+// the sql and mongo repositories enforce: a mismatched Version reports
+// model.ErrStaleWrite instead of silently overwriting someone else's write.
+func (r *UserRepository) Update(ctx context.Context, user *model.User) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, exists := r.users[user.Id]
+	if !exists {
+		return 0, nil
+	}
+	if existing.Version != user.Version {
+		return 0, model.ErrStaleWrite
+	}
+	user.Version = existing.Version + 1
+	r.users[user.Id] = *user
+	return 1, nil
+}
+
+func (r *UserRepository) Patch(ctx context.Context, patch map[string]interface{}) (int64, error) {
+	id, ok := patch["id"].(string)
+	if !ok {
+		return 0, fmt.Errorf("missing id")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, exists := r.users[id]
+	if !exists {
+		return 0, nil
+	}
+	if expectedVersion, hasVersion := patch["version"]; hasVersion && !versionMatches(user.Version, expectedVersion) {
+		return 0, model.ErrStaleWrite
+	}
+
+	fields := make(map[string]interface{}, len(patch))
+	for k, v := range patch {
+		if k == "version" {
+			continue
+		}
+		fields[k] = v
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(data, &user); err != nil {
+		return 0, err
+	}
+	user.Version++
+	r.users[id] = user
+	return 1, nil
+}
+
+// versionMatches compares current against a version read from a JSON-decoded
+// map, where it may have come through as a float64, int or int64 depending on
+// the caller.
+func versionMatches(current int64, raw interface{}) bool {
+	switch v := raw.(type) {
+	case float64:
+		return int64(v) == current
+	case int64:
+		return v == current
+	case int:
+		return int64(v) == current
+	default:
+		return false
+	}
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.users[id]; !exists {
+		return 0, nil
+	}
+	delete(r.users, id)
+	return 1, nil
+}
+
+func (r *UserRepository) Search(ctx context.Context, filter *model.UserFilter, limit int64, offset int64) ([]model.User, int64, error) {
+	all, _ := r.All(ctx)
+	matched := make([]model.User, 0, len(all))
+	for _, user := range all {
+		if filter.Id != "" && user.Id != filter.Id {
+			continue
+		}
+		if filter.Username != "" && !strings.HasPrefix(user.Username, filter.Username) {
+			continue
+		}
+		if filter.Email != "" && !strings.HasPrefix(user.Email, filter.Email) {
+			continue
+		}
+		if filter.Phone != "" && !strings.HasPrefix(user.Phone, filter.Phone) {
+			continue
+		}
+		matched = append(matched, user)
+	}
+
+	total := int64(len(matched))
+	if limit <= 0 {
+		return nil, total, nil
+	}
+	start := offset
+	if start > int64(len(matched)) {
+		start = int64(len(matched))
+	}
+	end := start + limit
+	if end > int64(len(matched)) {
+		end = int64(len(matched))
+	}
+	return matched[start:end], total, nil
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"go-service/internal/user/model"
+)
+
+func TestUserRepository_CreateLoad(t *testing.T) {
+	repo := NewUserRepository()
+	ctx := context.Background()
+
+	user := &model.User{Id: "u1", Username: "alice", Email: "alice@example.com"}
+	if _, err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if _, err := repo.Create(ctx, user); err == nil {
+		t.Error("Create with a duplicate id: want error, got nil")
+	}
+
+	loaded, err := repo.Load(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if loaded == nil || loaded.Username != "alice" {
+		t.Fatalf("Load = %+v, want username %q", loaded, "alice")
+	}
+
+	missing, err := repo.Load(ctx, "missing")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if missing != nil {
+		t.Errorf("Load(missing) = %+v, want nil", missing)
+	}
+}
+
+func TestUserRepository_UpdateStaleWrite(t *testing.T) {
+	repo := NewUserRepository()
+	ctx := context.Background()
+	user := &model.User{Id: "u1", Username: "alice"}
+	if _, err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	stale := &model.User{Id: "u1", Username: "alice2", Version: 1}
+	if _, err := repo.Update(ctx, stale); err != model.ErrStaleWrite {
+		t.Fatalf("Update with stale version: err = %v, want %v", err, model.ErrStaleWrite)
+	}
+
+	fresh := &model.User{Id: "u1", Username: "alice2", Version: 0}
+	affected, err := repo.Update(ctx, fresh)
+	if err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if affected != 1 {
+		t.Errorf("affected = %d, want 1", affected)
+	}
+	if fresh.Version != 1 {
+		t.Errorf("Version after update = %d, want 1", fresh.Version)
+	}
+}
+
+func TestUserRepository_Search(t *testing.T) {
+	repo := NewUserRepository()
+	ctx := context.Background()
+	_, _ = repo.Create(ctx, &model.User{Id: "u1", Username: "alice", Email: "alice@example.com"})
+	_, _ = repo.Create(ctx, &model.User{Id: "u2", Username: "bob", Email: "bob@example.com"})
+
+	users, total, err := repo.Search(ctx, &model.UserFilter{Username: "ali"}, 10, 0)
+	if err != nil {
+		t.Fatalf("Search returned error: %v", err)
+	}
+	if total != 1 || len(users) != 1 || users[0].Id != "u1" {
+		t.Fatalf("Search(username=ali) = %+v (total %d), want only u1", users, total)
+	}
+
+	users, total, err = repo.Search(ctx, &model.UserFilter{}, 10, 0)
+	if err != nil {
+		t.Fatalf("Search returned error: %v", err)
+	}
+	if total != 2 || len(users) != 2 {
+		t.Fatalf("Search(no filter) = %+v (total %d), want both rows", users, total)
+	}
+}
@@ -0,0 +1,169 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go-service/internal/user/model"
+)
+
+func NewUserRepository(db *mongo.Database) *UserRepository {
+	return &UserRepository{collection: db.Collection("users")}
+}
+
+type UserRepository struct {
+	collection *mongo.Collection
+}
+
+func (r *UserRepository) All(ctx context.Context) ([]model.User, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var users []model.User
+	err = cursor.All(ctx, &users)
+	return users, err
+}
+
+func (r *UserRepository) Load(ctx context.Context, id string) (*model.User, error) {
+	var user model.User
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// LoadMany fetches several users by id in a single query, so callers that
+// coalesce many individual Load calls (e.g. a GraphQL DataLoader) issue one round trip.
+func (r *UserRepository) LoadMany(ctx context.Context, ids []string) ([]model.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var users []model.User
+	err = cursor.All(ctx, &users)
+	return users, err
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *model.User) (int64, error) {
+	if _, err := r.collection.InsertOne(ctx, user); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Update replaces the document with an optimistic-concurrency check: the
+// filter matches both _id and the version the caller read, and a successful
+// write bumps version. A zero ModifiedCount is disambiguated by checkStaleWrite.
+func (r *UserRepository) Update(ctx context.Context, user *model.User) (int64, error) {
+	expectedVersion := user.Version
+	user.Version = expectedVersion + 1
+	res, err := r.collection.ReplaceOne(ctx, bson.M{"_id": user.Id, "version": expectedVersion}, user)
+	if err != nil {
+		return 0, err
+	}
+	if res.ModifiedCount == 0 {
+		user.Version = expectedVersion
+		return r.checkStaleWrite(ctx, user.Id)
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *UserRepository) Patch(ctx context.Context, user map[string]interface{}) (int64, error) {
+	id, ok := user["id"]
+	if !ok {
+		return 0, nil
+	}
+	filter := bson.M{"_id": id}
+	expectedVersion, hasVersion := user["version"]
+	if hasVersion {
+		filter["version"] = expectedVersion
+	}
+	fields := make(bson.M, len(user))
+	for k, v := range user {
+		if k == "id" || k == "version" {
+			continue
+		}
+		fields[k] = v
+	}
+	update := bson.M{"$set": fields, "$inc": bson.M{"version": 1}}
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	if res.ModifiedCount == 0 && hasVersion {
+		return r.checkStaleWrite(ctx, fmt.Sprint(id))
+	}
+	return res.ModifiedCount, nil
+}
+
+// checkStaleWrite disambiguates a zero-match write: if the document still
+// exists, somebody else's write already advanced its version past what the
+// caller expected, so report model.ErrStaleWrite; if it's gone, it's a plain
+// not-found.
+func (r *UserRepository) checkStaleWrite(ctx context.Context, id string) (int64, error) {
+	existing, err := r.Load(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if existing == nil {
+		return 0, nil
+	}
+	return 0, model.ErrStaleWrite
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) (int64, error) {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+func (r *UserRepository) Search(ctx context.Context, filter *model.UserFilter, limit int64, offset int64) ([]model.User, int64, error) {
+	if limit <= 0 {
+		return nil, 0, nil
+	}
+	query := bson.M{}
+	if filter.Id != "" {
+		query["_id"] = filter.Id
+	}
+	if filter.Username != "" {
+		query["username"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.Username)}
+	}
+	if filter.Email != "" {
+		query["email"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.Email)}
+	}
+	if filter.Phone != "" {
+		query["phone"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.Phone)}
+	}
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil || total == 0 {
+		return nil, total, err
+	}
+
+	opts := options.Find().SetLimit(limit).SetSkip(offset)
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, total, err
+	}
+	defer cursor.Close(ctx)
+	var users []model.User
+	err = cursor.All(ctx, &users)
+	return users, total, err
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go-service/internal/user/model"
+)
+
+const exportPageSize = 500
+
+type Repository interface {
+	All(ctx context.Context) ([]model.User, error)
+	Load(ctx context.Context, id string) (*model.User, error)
+	Create(ctx context.Context, user *model.User) (int64, error)
+	Update(ctx context.Context, user *model.User) (int64, error)
+	Patch(ctx context.Context, user map[string]interface{}) (int64, error)
+	Delete(ctx context.Context, id string) (int64, error)
+	Search(ctx context.Context, filter *model.UserFilter, limit int64, offset int64) ([]model.User, int64, error)
+}
+
+type UserService interface {
+	All(ctx context.Context) ([]model.User, error)
+	Load(ctx context.Context, id string) (*model.User, error)
+	Create(ctx context.Context, user *model.User) (int64, error)
+	Update(ctx context.Context, user *model.User) (int64, error)
+	Patch(ctx context.Context, user map[string]interface{}) (int64, error)
+	Delete(ctx context.Context, id string) (int64, error)
+	Search(ctx context.Context, filter *model.UserFilter, limit int64, offset int64) ([]model.User, int64, error)
+	Import(ctx context.Context, users []model.User) ([]int64, []error)
+	Export(ctx context.Context, filter *model.UserFilter, fn func(*model.User) error) error
+	LoadMany(ctx context.Context, ids []string) ([]model.User, error)
+}
+
+// batchRepository is implemented by repositories that can satisfy several Load
+// calls in one round trip (e.g. UserAdapter.LoadMany). Repositories that don't
+// implement it still work: LoadMany falls back to one Load per id.
+type batchRepository interface {
+	LoadMany(ctx context.Context, ids []string) ([]model.User, error)
+}
+
+// transactionalRepository is implemented by repositories backed by a store that
+// supports transactions (e.g. UserAdapter over database/sql). Repositories that
+// don't implement it still work: mutate just runs fn without a surrounding
+// transaction.
+type transactionalRepository interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// batchCreator is implemented by repositories that can insert many rows in a
+// single round trip (e.g. UserAdapter, via one multi-row INSERT). Repositories
+// that don't implement it still work: Import falls back to one Create call per
+// row, the same round-trip cost it had before batching existed.
+type batchCreator interface {
+	CreateMany(ctx context.Context, users []model.User) ([]int64, []error)
+}
+
+// OutboxRecorder records a user lifecycle event for the webhook dispatcher to
+// deliver later. It's called inside the same transaction as the user mutation
+// when the repository supports one, giving at-least-once, exactly-once-written delivery.
+type OutboxRecorder interface {
+	Record(ctx context.Context, eventType string, payload interface{}) error
+}
+
+func NewUserService(repository Repository, outbox OutboxRecorder) UserService {
+	return &userService{repository: repository, outbox: outbox}
+}
+
+type userService struct {
+	repository Repository
+	outbox     OutboxRecorder
+}
+
+// mutate runs fn - and, if it succeeds and affects a row, records eventType
+// with payload to the outbox - inside a single transaction when the repository
+// supports one, so the user table and the outbox table never disagree.
+func (svc *userService) mutate(ctx context.Context, eventType string, payload interface{}, fn func(ctx context.Context) (int64, error)) (int64, error) {
+	if svc.outbox == nil {
+		return fn(ctx)
+	}
+
+	var affected int64
+	run := func(ctx context.Context) error {
+		var err error
+		affected, err = fn(ctx)
+		if err != nil || affected == 0 {
+			return err
+		}
+		return svc.outbox.Record(ctx, eventType, payload)
+	}
+
+	if tx, ok := svc.repository.(transactionalRepository); ok {
+		return affected, tx.WithTransaction(ctx, run)
+	}
+	return affected, run(ctx)
+}
+
+func (svc *userService) All(ctx context.Context) ([]model.User, error) {
+	return svc.repository.All(ctx)
+}
+
+func (svc *userService) Load(ctx context.Context, id string) (*model.User, error) {
+	return svc.repository.Load(ctx, id)
+}
+
+func (svc *userService) Create(ctx context.Context, user *model.User) (int64, error) {
+	// Version is server-controlled: a caller can't seed the optimistic-concurrency
+	// counter of a brand new row through the request body.
+	user.Version = 0
+	return svc.mutate(ctx, "user.created", user, func(ctx context.Context) (int64, error) {
+		return svc.repository.Create(ctx, user)
+	})
+}
+
+func (svc *userService) Update(ctx context.Context, user *model.User) (int64, error) {
+	return svc.mutate(ctx, "user.updated", user, func(ctx context.Context) (int64, error) {
+		return svc.repository.Update(ctx, user)
+	})
+}
+
+func (svc *userService) Patch(ctx context.Context, user map[string]interface{}) (int64, error) {
+	return svc.mutate(ctx, "user.patched", user, func(ctx context.Context) (int64, error) {
+		return svc.repository.Patch(ctx, user)
+	})
+}
+
+func (svc *userService) Delete(ctx context.Context, id string) (int64, error) {
+	return svc.mutate(ctx, "user.deleted", &model.User{Id: id}, func(ctx context.Context) (int64, error) {
+		return svc.repository.Delete(ctx, id)
+	})
+}
+
+func (svc *userService) Search(ctx context.Context, filter *model.UserFilter, limit int64, offset int64) ([]model.User, int64, error) {
+	return svc.repository.Search(ctx, filter, limit, offset)
+}
+
+func (svc *userService) LoadMany(ctx context.Context, ids []string) ([]model.User, error) {
+	if batch, ok := svc.repository.(batchRepository); ok {
+		return batch.LoadMany(ctx, ids)
+	}
+	users := make([]model.User, 0, len(ids))
+	for _, id := range ids {
+		user, err := svc.repository.Load(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+// Import persists a batch of rows in as few round trips as the repository
+// allows - a repository that implements batchCreator (e.g. UserAdapter, via
+// one multi-row INSERT) gets the whole batch in one - and reports one error
+// per row (nil for the rows that succeeded), so the caller can tell exactly
+// which rows need fixing and resume from those. Every row is attempted
+// independently: one row failing never stops the rest of the batch.
+func (svc *userService) Import(ctx context.Context, users []model.User) ([]int64, []error) {
+	// Same rule as Create: version is server-controlled, not something an
+	// imported row can seed.
+	for i := range users {
+		users[i].Version = 0
+	}
+
+	if batch, ok := svc.repository.(batchCreator); ok {
+		return batch.CreateMany(ctx, users)
+	}
+
+	results := make([]int64, len(users))
+	errs := make([]error, len(users))
+	for i := range users {
+		affected, err := svc.repository.Create(ctx, &users[i])
+		results[i] = affected
+		if err != nil {
+			errs[i] = fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return results, errs
+}
+
+// Export streams the filtered users to fn page by page instead of loading the
+// whole result set into memory, so tables with millions of rows don't blow the heap.
+func (svc *userService) Export(ctx context.Context, filter *model.UserFilter, fn func(*model.User) error) error {
+	var offset int64
+	for {
+		page, _, err := svc.repository.Search(ctx, filter, exportPageSize, offset)
+		if err != nil {
+			return err
+		}
+		for i := range page {
+			if err := fn(&page[i]); err != nil {
+				return err
+			}
+		}
+		if int64(len(page)) < exportPageSize {
+			return nil
+		}
+		offset += exportPageSize
+	}
+}
@@ -1,16 +1,20 @@
 package user
 
 import (
-	"database/sql"
+	"fmt"
 	"net/http"
 
 	"github.com/core-go/core"
 	v "github.com/core-go/core/validator"
 	"github.com/core-go/search/query"
-	"github.com/core-go/sql/repository"
 
+	"go-service/internal/storage"
+	"go-service/internal/user/graph"
 	"go-service/internal/user/handler"
 	"go-service/internal/user/model"
+	"go-service/internal/user/repository/adapter"
+	"go-service/internal/user/repository/memory"
+	"go-service/internal/user/repository/mongo"
 	"go-service/internal/user/service"
 )
 
@@ -22,20 +26,59 @@ type UserTransport interface {
 	Update(w http.ResponseWriter, r *http.Request)
 	Patch(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	Import(w http.ResponseWriter, r *http.Request)
+	Export(w http.ResponseWriter, r *http.Request)
 }
 
-func NewUserHandler(db *sql.DB, logError core.Log) (UserTransport, error) {
+// newUserRepository picks the concrete repository implementation for st's
+// storage backend. Every branch satisfies service.Repository, so the rest of
+// the stack doesn't need to know which one is in play.
+func newUserRepository(st storage.Storage) (service.Repository, error) {
+	switch backend := st.(type) {
+	case *storage.SqlStorage:
+		buildQuery := query.UseQuery[model.User, *model.UserFilter](backend.DB, "users")
+		return adapter.NewUserAdapter(backend.DB, buildQuery)
+	case *storage.MongoStorage:
+		return mongo.NewUserRepository(backend.Database), nil
+	case *storage.MemoryStorage:
+		return memory.NewUserRepository(), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage kind %q", st.Kind())
+	}
+}
+
+func newUserDeps(st storage.Storage, outbox service.OutboxRecorder) (service.UserService, error) {
+	userRepository, err := newUserRepository(st)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewUserService(userRepository, outbox), nil
+}
+
+// NewUserHandler builds the REST transport. outbox is nil unless st is backed
+// by a database/sql connection a webhook.Repository was set up against; Create/
+// Update/Patch/Delete skip outbox recording when it's nil.
+func NewUserHandler(st storage.Storage, outbox service.OutboxRecorder, logError core.Log, action *core.ActionConfig) (UserTransport, error) {
 	validator, err := v.NewValidator[*model.User]()
 	if err != nil {
 		return nil, err
 	}
 
-	buildQuery := query.UseQuery[model.User, *model.UserFilter](db, "users")
-	userRepository, err := repository.NewSearchRepository[model.User, string, *model.UserFilter](db, "users", buildQuery)
+	userService, err := newUserDeps(st, outbox)
 	if err != nil {
 		return nil, err
 	}
-	userService := service.NewUserService(db, userRepository)
-	userHandler := handler.NewUserHandler(userService, logError, validator.Validate)
+	userHandler := handler.NewUserHandler(userService, logError, validator.Validate, action)
 	return userHandler, nil
 }
+
+// NewUserGraphHandler builds the GraphQL transport alongside the REST handler,
+// reusing the same service.UserService and model.UserFilter.
+func NewUserGraphHandler(st storage.Storage, outbox service.OutboxRecorder) (*graph.Handler, error) {
+	userService, err := newUserDeps(st, outbox)
+	if err != nil {
+		return nil, err
+	}
+	resolver := graph.NewResolver(userService, graph.NewEventBroker())
+	return graph.NewHandler(resolver, userService), nil
+}
@@ -0,0 +1,67 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/core-go/core"
+
+	"go-service/internal/storage"
+	"go-service/internal/user/model"
+)
+
+// TestUserHandler_MemoryBackend exercises the REST handler stack end to end
+// over a real httptest server against storage.MemoryStorage - the capability
+// the in-memory backend exists for: a handler-level test with no database.
+func TestUserHandler_MemoryBackend(t *testing.T) {
+	var logError core.Log = func(ctx context.Context, message string) {}
+	transport, err := NewUserHandler(storage.NewMemoryStorage(), nil, logError, &core.ActionConfig{})
+	if err != nil {
+		t.Fatalf("NewUserHandler returned error: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			transport.Create(w, r)
+			return
+		}
+		transport.All(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	user := model.User{Id: "u1", Username: "alice", Email: "alice@example.com", Phone: "555-0100"}
+	body, err := json.Marshal(user)
+	if err != nil {
+		t.Fatalf("marshaling request body: %v", err)
+	}
+	res, err := http.Post(server.URL+"/users", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /users: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /users status = %d, want %d", res.StatusCode, http.StatusCreated)
+	}
+
+	res, err = http.Get(server.URL + "/users")
+	if err != nil {
+		t.Fatalf("GET /users: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("GET /users status = %d, want %d", res.StatusCode, http.StatusOK)
+	}
+	var got []map[string]interface{}
+	if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(got) != 1 || got[0]["username"] != "alice" {
+		t.Fatalf("GET /users = %+v, want one row for alice", got)
+	}
+}
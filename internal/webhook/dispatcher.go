@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = 50
+)
+
+// delivery is the JSON body POSTed to every subscriber for one outbox event.
+type delivery struct {
+	Event     string          `json:"event"`
+	User      json.RawMessage `json:"user"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// Dispatcher polls the outbox table and delivers claimed events to every
+// registered subscriber at least once, signing each request body with
+// HMAC-SHA256 using the subscriber's own secret so receivers can verify it
+// came from us. Run is started as a goroutine from app.NewApp; several app
+// instances can run their own Dispatcher against the same table because
+// Repository.Claim's SELECT ... FOR UPDATE SKIP LOCKED keeps them from
+// redelivering the same event.
+type Dispatcher struct {
+	Repository   *Repository
+	Client       *http.Client
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+func NewDispatcher(repository *Repository) *Dispatcher {
+	return &Dispatcher{
+		Repository:   repository,
+		Client:       &http.Client{Timeout: 10 * time.Second},
+		PollInterval: defaultPollInterval,
+		BatchSize:    defaultBatchSize,
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	interval := d.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.poll(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) poll(ctx context.Context) {
+	batchSize := d.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	events, err := d.Repository.Claim(ctx, batchSize)
+	if err != nil {
+		return
+	}
+	for _, event := range events {
+		d.deliver(ctx, event)
+	}
+}
+
+// deliver sends event to every registered subscriber. If any subscriber
+// rejects the request or is unreachable, the whole event is reported failed
+// so it's retried for every subscriber on the next eligible poll - duplicate
+// deliveries to subscribers that already received it are the price of
+// at-least-once delivery.
+func (d *Dispatcher) deliver(ctx context.Context, event Event) {
+	subscribers, err := d.Repository.Subscribers(ctx)
+	if err != nil {
+		_ = d.Repository.MarkFailed(ctx, event, err)
+		return
+	}
+	if len(subscribers) == 0 {
+		_ = d.Repository.MarkDelivered(ctx, event.Id)
+		return
+	}
+
+	body, err := json.Marshal(delivery{Event: event.EventType, User: json.RawMessage(event.Payload), Timestamp: event.CreatedAt})
+	if err != nil {
+		_ = d.Repository.MarkFailed(ctx, event, err)
+		return
+	}
+
+	for _, sub := range subscribers {
+		if err := d.send(ctx, sub, body); err != nil {
+			_ = d.Repository.MarkFailed(ctx, event, fmt.Errorf("subscriber %s: %w", sub.Id, err))
+			return
+		}
+	}
+	_ = d.Repository.MarkDelivered(ctx, event.Id)
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub Subscriber, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", sign(sub.Secret, body))
+
+	res, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("subscriber returned status %d", res.StatusCode)
+	}
+	return nil
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"user.created"}`)
+
+	got := sign("top-secret", body)
+
+	mac := hmac.New(sha256.New, []byte("top-secret"))
+	mac.Write(body)
+	want := hex.EncodeToString(mac.Sum(nil))
+	if got != want {
+		t.Fatalf("sign() = %s, want %s", got, want)
+	}
+
+	if other := sign("different-secret", body); other == got {
+		t.Fatalf("sign() with a different secret produced the same signature")
+	}
+}
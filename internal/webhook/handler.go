@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/core-go/core"
+)
+
+// Handler exposes the /webhooks admin endpoint used to register, list and
+// remove the subscriber URLs Dispatcher delivers events to.
+type Handler struct {
+	Repository *Repository
+}
+
+func NewHandler(repository *Repository) *Handler {
+	return &Handler{Repository: repository}
+}
+
+// subscriberView is Subscriber with Secret omitted, since the HMAC signing
+// secret must never be readable back out through the admin list endpoint -
+// anyone who could read it could forge X-Signature headers.
+type subscriberView struct {
+	Id        string    `json:"id"`
+	Url       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	subscribers, err := h.Repository.Subscribers(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	views := make([]subscriberView, len(subscribers))
+	for i, sub := range subscribers {
+		views[i] = subscriberView{Id: sub.Id, Url: sub.Url, CreatedAt: sub.CreatedAt}
+	}
+	core.JSON(w, http.StatusOK, views)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var sub Subscriber
+	if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sub.Id == "" || sub.Url == "" || sub.Secret == "" {
+		http.Error(w, "id, url and secret are required", http.StatusBadRequest)
+		return
+	}
+	sub.CreatedAt = time.Now()
+	if err := h.Repository.CreateSubscriber(r.Context(), &sub); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	core.JSON(w, http.StatusCreated, sub)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := core.GetRequiredString(w, r)
+	if err != nil {
+		return
+	}
+	if err := h.Repository.DeleteSubscriber(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
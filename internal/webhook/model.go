@@ -0,0 +1,23 @@
+package webhook
+
+import "time"
+
+type Subscriber struct {
+	Id        string    `json:"id" gorm:"column:id;primary_key" validate:"required,max=40"`
+	Url       string    `json:"url" gorm:"column:url" validate:"required,url,max=500"`
+	Secret    string    `json:"secret" gorm:"column:secret" validate:"required,max=200"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+// Event is one row of the user_outbox table: a durable record of a user
+// lifecycle event, written in the same transaction as the mutation that
+// produced it and delivered to subscribers at least once by the Dispatcher.
+type Event struct {
+	Id          int64      `json:"id" gorm:"column:id;primary_key"`
+	EventType   string     `json:"eventType" gorm:"column:event_type"`
+	Payload     []byte     `json:"payload" gorm:"column:payload"`
+	CreatedAt   time.Time  `json:"createdAt" gorm:"column:created_at"`
+	ClaimedAt   *time.Time `json:"claimedAt,omitempty" gorm:"column:claimed_at"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty" gorm:"column:delivered_at"`
+	Attempts    int        `json:"attempts" gorm:"column:attempts"`
+}
@@ -0,0 +1,203 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	s "github.com/core-go/sql"
+)
+
+const defaultMaxRetries = 8
+
+// Repository persists outbox events, subscriber registrations and dead
+// letters behind database/sql, so the Dispatcher and Repository.Record share
+// the same connection - and, during Record, the same transaction - as the
+// user mutation that produced the event.
+type Repository struct {
+	DB         *sql.DB
+	MaxRetries int
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{DB: db, MaxRetries: defaultMaxRetries}
+}
+
+// Migrate creates the outbox, dead-letter and subscriber tables if they don't
+// already exist, so app.NewApp can call it next to storage.Storage.Migrate.
+func (r *Repository) Migrate(ctx context.Context) error {
+	statements := []string{
+		`create table if not exists user_outbox (
+			id bigserial primary key,
+			event_type varchar(100) not null,
+			payload jsonb not null,
+			created_at timestamp not null default now(),
+			claimed_at timestamp,
+			delivered_at timestamp,
+			attempts int not null default 0
+		)`,
+		`create index if not exists user_outbox_poll_idx on user_outbox (delivered_at, claimed_at)`,
+		`create table if not exists user_outbox_dead_letter (
+			id bigint primary key,
+			event_type varchar(100) not null,
+			payload jsonb not null,
+			created_at timestamp not null,
+			attempts int not null,
+			failed_at timestamp not null default now(),
+			error text
+		)`,
+		`create table if not exists webhook_subscribers (
+			id varchar(40) primary key,
+			url varchar(500) not null,
+			secret varchar(200) not null,
+			created_at timestamp not null default now()
+		)`,
+	}
+	for _, stmt := range statements {
+		if _, err := r.DB.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record inserts one outbox row inside the transaction bound to ctx (via
+// s.GetTx), so it commits or rolls back together with whatever user mutation
+// produced eventType. This is what makes service.UserService.Create/Update/
+// Patch/Delete write-ahead the event, rather than risk losing it.
+func (r *Repository) Record(ctx context.Context, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	tx := s.GetTx(ctx, r.DB)
+	_, err = tx.ExecContext(ctx, `insert into user_outbox (event_type, payload) values ($1, $2)`, eventType, data)
+	return err
+}
+
+// Claim locks up to limit undelivered rows with SELECT ... FOR UPDATE SKIP
+// LOCKED and stamps claimed_at, so several Dispatcher instances can poll the
+// same table concurrently without double-delivering an event. A row becomes
+// claimable again once its exponential backoff window (2^attempts seconds
+// since it was last claimed) has elapsed.
+func (r *Repository) Claim(ctx context.Context, limit int) ([]Event, error) {
+	var events []Event
+	err := s.Execute(ctx, r.DB, func(ctx context.Context) error {
+		tx := s.GetTx(ctx, r.DB)
+		rows, err := tx.QueryContext(ctx, `
+			select id, event_type, payload, created_at, claimed_at, delivered_at, attempts
+			from user_outbox
+			where delivered_at is null
+			  and (claimed_at is null or claimed_at < now() - (power(2, attempts) * interval '1 second'))
+			order by created_at
+			limit $1
+			for update skip locked`, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var ids []int64
+		for rows.Next() {
+			var event Event
+			if err := rows.Scan(&event.Id, &event.EventType, &event.Payload, &event.CreatedAt, &event.ClaimedAt, &event.DeliveredAt, &event.Attempts); err != nil {
+				return err
+			}
+			events = append(events, event)
+			ids = append(ids, event.Id)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if _, err := tx.ExecContext(ctx, `update user_outbox set claimed_at = now() where id = $1`, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return events, err
+}
+
+func (r *Repository) MarkDelivered(ctx context.Context, id int64) error {
+	_, err := r.DB.ExecContext(ctx, `update user_outbox set delivered_at = now() where id = $1`, id)
+	return err
+}
+
+// MarkFailed records one more failed delivery attempt. Once attempts reaches
+// MaxRetries the event is moved out of the outbox into the dead-letter table
+// instead of being retried forever.
+func (r *Repository) MarkFailed(ctx context.Context, event Event, deliveryErr error) error {
+	attempts := event.Attempts + 1
+	if attempts >= r.maxRetries() {
+		return r.deadLetter(ctx, event, attempts, deliveryErr)
+	}
+	_, err := r.DB.ExecContext(ctx, `update user_outbox set attempts = $2 where id = $1`, event.Id, attempts)
+	return err
+}
+
+func (r *Repository) maxRetries() int {
+	if r.MaxRetries > 0 {
+		return r.MaxRetries
+	}
+	return defaultMaxRetries
+}
+
+func (r *Repository) deadLetter(ctx context.Context, event Event, attempts int, deliveryErr error) error {
+	errMessage := ""
+	if deliveryErr != nil {
+		errMessage = deliveryErr.Error()
+	}
+	return s.Execute(ctx, r.DB, func(ctx context.Context) error {
+		tx := s.GetTx(ctx, r.DB)
+		_, err := tx.ExecContext(ctx, `
+			insert into user_outbox_dead_letter (id, event_type, payload, created_at, attempts, error)
+			values ($1, $2, $3, $4, $5, $6)`,
+			event.Id, event.EventType, event.Payload, event.CreatedAt, attempts, errMessage)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `delete from user_outbox where id = $1`, event.Id)
+		return err
+	})
+}
+
+func (r *Repository) Subscribers(ctx context.Context) ([]Subscriber, error) {
+	rows, err := r.DB.QueryContext(ctx, `select id, url, secret, created_at from webhook_subscribers`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscribers []Subscriber
+	for rows.Next() {
+		var sub Subscriber
+		if err := rows.Scan(&sub.Id, &sub.Url, &sub.Secret, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+		subscribers = append(subscribers, sub)
+	}
+	return subscribers, rows.Err()
+}
+
+func (r *Repository) CreateSubscriber(ctx context.Context, sub *Subscriber) error {
+	_, err := r.DB.ExecContext(ctx, `insert into webhook_subscribers (id, url, secret, created_at) values ($1, $2, $3, $4)`,
+		sub.Id, sub.Url, sub.Secret, sub.CreatedAt)
+	return err
+}
+
+func (r *Repository) DeleteSubscriber(ctx context.Context, id string) error {
+	res, err := r.DB.ExecContext(ctx, `delete from webhook_subscribers where id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("subscriber %q not found", id)
+	}
+	return nil
+}